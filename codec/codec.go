@@ -0,0 +1,235 @@
+// Package codec provides format-agnostic encoding and decoding of
+// Reassembly ship and fleet files. It detects the serialization format
+// (JSON or YAML) and whether content is gzip-compressed from the file
+// extension, falling back to sniffing the content itself when the
+// extension is missing or unrecognised.
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies the serialization codec used to encode a file's
+// content.
+type Format int
+
+const (
+	// FormatUnknown means the format could not be determined from the
+	// file extension and must be sniffed from content.
+	FormatUnknown Format = iota
+	FormatJSON
+	FormatYAML
+)
+
+// SaveOptions controls how Encode writes data to a file.
+type SaveOptions struct {
+	// Format selects the codec to use. If left as FormatUnknown, the
+	// format is inferred from the destination path's extension.
+	Format Format
+	// Gzip compresses the encoded output. A destination path ending in
+	// ".gz" implies Gzip and need not set it explicitly.
+	Gzip bool
+}
+
+// DetectFormat inspects path's extension, stripping a trailing ".gz", and
+// returns the format it implies along with whether the path is gzipped.
+// It returns FormatUnknown if the extension is missing or unrecognised.
+func DetectFormat(path string) (format Format, gzipped bool) {
+	path = strings.ToLower(path)
+
+	ext := filepath.Ext(path)
+	if ext == ".gz" {
+		gzipped = true
+		ext = filepath.Ext(strings.TrimSuffix(path, ext))
+	}
+
+	switch ext {
+	case ".yaml", ".yml":
+		return FormatYAML, gzipped
+	case ".json":
+		return FormatJSON, gzipped
+	default:
+		return FormatUnknown, gzipped
+	}
+}
+
+// Sniff guesses the format of content when the file extension is missing
+// or unrecognised. JSON content is detected by a leading '{'; anything
+// else is assumed to be YAML, since YAML has no reliable magic byte.
+func Sniff(content []byte) Format {
+	trimmed := bytes.TrimLeft(content, " \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// IsGzip reports whether content begins with the gzip magic number.
+func IsGzip(content []byte) bool {
+	return len(content) >= 2 && content[0] == 0x1f && content[1] == 0x8b
+}
+
+// NewReader peeks the first two bytes of r and, if they match the gzip
+// magic number, wraps r in a gzip.Reader; otherwise a buffered reader over
+// r is returned with its peeked bytes intact. The gzip header is not validated until the first
+// Read call, so a malformed gzip stream surfaces its error there rather
+// than from NewReader itself.
+func NewReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(2)
+	if err != nil || !IsGzip(magic) {
+		return br
+	}
+
+	return &lazyGzipReader{src: br}
+}
+
+// lazyGzipReader defers constructing the underlying gzip.Reader until the
+// first Read, so that NewReader can report gzip.Reader's construction
+// error (e.g. a malformed header) through the io.Reader interface instead
+// of returning it directly.
+type lazyGzipReader struct {
+	src io.Reader
+	gz  *gzip.Reader
+	err error
+}
+
+func (l *lazyGzipReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	if l.gz == nil {
+		if l.gz, l.err = gzip.NewReader(l.src); l.err != nil {
+			return 0, l.err
+		}
+	}
+
+	return l.gz.Read(p)
+}
+
+// DecodeReader reads all of r, transparently decompressing gzip content,
+// sniffs whether the result is JSON or YAML, and unmarshals it into v. A
+// reader has no file extension to consult, so the format is always
+// sniffed from content.
+func DecodeReader(r io.Reader, v any) error {
+	content, err := io.ReadAll(NewReader(r))
+	if err != nil {
+		return err
+	}
+
+	if Sniff(content) == FormatYAML {
+		return yaml.Unmarshal(content, v)
+	}
+
+	return json.Unmarshal(content, v)
+}
+
+// EncodeWriter marshals v according to opts and writes it to w,
+// gzip-compressing the output when opts.Gzip is set.
+func EncodeWriter(w io.Writer, v any, opts SaveOptions) error {
+	var b []byte
+	var err error
+	if opts.Format == FormatYAML {
+		b, err = yaml.Marshal(v)
+	} else {
+		b, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	return WriteTo(w, b, opts.Gzip)
+}
+
+// Decode reads path from disk, transparently decompressing gzip content
+// and selecting a JSON or YAML decoder by extension (falling back to
+// content sniffing), then unmarshals the result into v.
+func Decode(path string, v any) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(NewReader(file))
+	if err != nil {
+		return err
+	}
+
+	format, _ := DetectFormat(path)
+	if format == FormatUnknown {
+		format = Sniff(content)
+	}
+
+	if format == FormatYAML {
+		return yaml.Unmarshal(content, v)
+	}
+
+	return json.Unmarshal(content, v)
+}
+
+// Encode marshals v according to opts and writes it to path, creating the
+// file if it does not already exist.
+func Encode(path string, v any, opts SaveOptions) error {
+	format, gzipped := opts.Format, opts.Gzip
+	extFormat, extGzip := DetectFormat(path)
+	if format == FormatUnknown {
+		format = extFormat
+	}
+	if extGzip {
+		gzipped = true
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return EncodeWriter(file, v, SaveOptions{Format: format, Gzip: gzipped})
+}
+
+// WriteFile writes pre-encoded bytes b to path, gzip-compressing them
+// first when gzipped is set. It is Encode's file-writing half, exposed
+// for callers that need to marshal v themselves (e.g. rsmships'
+// SaveShipHex, which substitutes hex-encoded block IDs before
+// marshalling) but still want Encode's gzip handling.
+func WriteFile(path string, b []byte, gzipped bool) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return WriteTo(file, b, gzipped)
+}
+
+// WriteTo writes pre-encoded bytes b to w, gzip-compressing them first
+// when gzipped is set. It is EncodeWriter's file-writing half; see
+// WriteFile.
+func WriteTo(w io.Writer, b []byte, gzipped bool) error {
+	if !gzipped {
+		_, err := w.Write(b)
+		return err
+	}
+
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	_, err = gz.Write(b)
+	return err
+}
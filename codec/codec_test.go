@@ -0,0 +1,100 @@
+package codec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	cases := []struct {
+		path        string
+		wantFormat  Format
+		wantGzipped bool
+	}{
+		{"ship.json", FormatJSON, false},
+		{"ship.JSON", FormatJSON, false},
+		{"ship.yaml", FormatYAML, false},
+		{"ship.yml", FormatYAML, false},
+		{"ship.json.gz", FormatJSON, true},
+		{"ship.yaml.gz", FormatYAML, true},
+		{"ship.json.GZ", FormatJSON, true},
+		{"ship.YAML.GZ", FormatYAML, true},
+		{"ship.gz", FormatUnknown, true},
+		{"ship", FormatUnknown, false},
+	}
+
+	for _, c := range cases {
+		format, gzipped := DetectFormat(c.path)
+		if format != c.wantFormat || gzipped != c.wantGzipped {
+			t.Errorf("DetectFormat(%q) = (%v, %v), want (%v, %v)", c.path, format, gzipped, c.wantFormat, c.wantGzipped)
+		}
+	}
+}
+
+func TestSniff(t *testing.T) {
+	if got := Sniff([]byte(`  {"a": 1}`)); got != FormatJSON {
+		t.Errorf("Sniff(JSON) = %v, want FormatJSON", got)
+	}
+	if got := Sniff([]byte("a: 1\n")); got != FormatYAML {
+		t.Errorf("Sniff(YAML) = %v, want FormatYAML", got)
+	}
+}
+
+func TestNewReaderTransparentGzip(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte(`{"a": 1}`))
+	gz.Close()
+
+	r := NewReader(bytes.NewReader(gzipped.Bytes()))
+	var v map[string]int
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		t.Fatalf("decode gzip-wrapped content: %v", err)
+	}
+	if v["a"] != 1 {
+		t.Errorf("decoded %v, want a=1", v)
+	}
+
+	r2 := NewReader(bytes.NewReader([]byte(`{"a": 2}`)))
+	var v2 map[string]int
+	if err := json.NewDecoder(r2).Decode(&v2); err != nil {
+		t.Fatalf("decode plain content: %v", err)
+	}
+	if v2["a"] != 2 {
+		t.Errorf("decoded %v, want a=2", v2)
+	}
+}
+
+func TestDecodeReaderSniffsYAML(t *testing.T) {
+	var v struct {
+		A int `json:"a" yaml:"a"`
+	}
+
+	if err := DecodeReader(bytes.NewReader([]byte("a: 3\n")), &v); err != nil {
+		t.Fatalf("DecodeReader: %v", err)
+	}
+	if v.A != 3 {
+		t.Errorf("A = %d, want 3", v.A)
+	}
+}
+
+func TestEncodeWriterGzip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeWriter(&buf, map[string]int{"a": 1}, SaveOptions{Format: FormatJSON, Gzip: true}); err != nil {
+		t.Fatalf("EncodeWriter: %v", err)
+	}
+
+	if !IsGzip(buf.Bytes()) {
+		t.Fatal("EncodeWriter with Gzip set did not produce gzip-magic output")
+	}
+
+	var v map[string]int
+	if err := DecodeReader(&buf, &v); err != nil {
+		t.Fatalf("DecodeReader round-trip: %v", err)
+	}
+	if v["a"] != 1 {
+		t.Errorf("round-tripped %v, want a=1", v)
+	}
+}
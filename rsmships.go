@@ -1,9 +1,16 @@
 package rsmships
 
 import (
-	"compress/gzip"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SomeDebris/rsmships-go/codec"
 )
 
 // command structure. Only stores command flags (e.g. ALWAYS_MANEUVER, AI_BINDING,
@@ -12,14 +19,63 @@ type CommandData struct {
 	// Command flags change the behavior of a ship's AI. Tournament mode
 	// overwrites all command flags except for ALWAYS_MANEUVER, ALWAYS_KITE,
 	// ALWAYS_RUSH, and AI_BINDING.
-	//
-	// This is stored as a json.RawMessage, as Reassembly will serialize this
-	// feld as a single string instead of a string array when only one flag is
-	// specified.
-	Flags   json.RawMessage `json:"flags,omitempty"`
+	Flags   CommandFlags `json:"flags,omitempty" yaml:"flags,omitempty"`
 	// The faction the command belongs to. Tournament mode overwrites this
 	// value to 100, 101, 102, etc.
-	Faction int             `json:"faction,omitempty"`
+	Faction int          `json:"faction,omitempty" yaml:"faction,omitempty"`
+}
+
+// CommandFlag is a single AI behavior flag accepted by CommandData.Flags.
+type CommandFlag string
+
+const (
+	FlagAlwaysManeuver CommandFlag = "ALWAYS_MANEUVER"
+	FlagAlwaysKite     CommandFlag = "ALWAYS_KITE"
+	FlagAlwaysRush     CommandFlag = "ALWAYS_RUSH"
+	FlagAIBinding      CommandFlag = "AI_BINDING"
+)
+
+// CommandFlags is the list of flags set on a CommandData. Reassembly
+// serializes a single flag as a bare JSON string rather than a one-element
+// array, so CommandFlags implements custom UnmarshalJSON/MarshalJSON to
+// accept and preserve both forms.
+type CommandFlags []CommandFlag
+
+// UnmarshalJSON accepts either a bare JSON string or a JSON array of
+// strings, matching the two forms Reassembly produces.
+func (f *CommandFlags) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || string(data) == "null" {
+		*f = nil
+		return nil
+	}
+
+	if data[0] == '[' {
+		var flags []CommandFlag
+		if err := json.Unmarshal(data, &flags); err != nil {
+			return err
+		}
+
+		*f = flags
+		return nil
+	}
+
+	var flag CommandFlag
+	if err := json.Unmarshal(data, &flag); err != nil {
+		return err
+	}
+
+	*f = CommandFlags{flag}
+	return nil
+}
+
+// MarshalJSON emits a bare string when f has exactly one flag, and a JSON
+// array otherwise, preserving round-trip fidelity with Reassembly.
+func (f CommandFlags) MarshalJSON() ([]byte, error) {
+	if len(f) == 1 {
+		return json.Marshal(f[0])
+	}
+
+	return json.Marshal([]CommandFlag(f))
 }
 
 // Block structure. Stores basic information needed for Tournaments. Because not
@@ -28,33 +84,137 @@ type CommandData struct {
 // intentional, as modifying blocks is not illegal in most Reassembly
 // Tournaments.
 type Block struct {
-	// Unqique block ID. Used to identify the block used by the ship.
-	//
-	// Block IDs are stored as json.RawMessage, as Reassembly will sometimes
-	// serialize integers as hexadecimal values. This does not conform to the JSON
-	// standard, and, as such, cannot be unmarshalled by encoding/json.
-	Id        json.RawMessage `json:"ident"`
+	// Unqique block ID. Used to identify the block used by the ship. A nil
+	// Id means the source JSON omitted "ident" or set it to null, distinct
+	// from a present "ident" of 0; see RemoveNilIds.
+	Id        *BlockID     `json:"ident" yaml:"ident"`
 	// distance in X and Y between the ship's origin (usually set to its center of mass on
 	// export) and the centroid of the block.
-	Offset    [2]float64      `json:"offset"`
-	Angle     float64         `json:"angle"`
-	Command   *CommandData    `json:"command,omitempty"`
-	BindingId int             `json:"bindingId,omitempty"`
+	Offset    [2]float64   `json:"offset" yaml:"offset"`
+	Angle     float64      `json:"angle" yaml:"angle"`
+	Command   *CommandData `json:"command,omitempty" yaml:"command,omitempty"`
+	BindingId int          `json:"bindingId,omitempty" yaml:"bindingId,omitempty"`
+}
+
+// BlockID uniquely identifies the block used by a ship. Reassembly usually
+// serializes IDs as a plain JSON number, but sometimes emits a "0x"-prefixed
+// hexadecimal string instead; a plain uint32 field would fail to unmarshal
+// whichever form it didn't expect.
+type BlockID uint32
+
+// UnmarshalJSON accepts a block ID as either a decimal JSON number or a
+// "0x"-prefixed hexadecimal JSON string, matching both forms Reassembly
+// produces.
+func (id *BlockID) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		v, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 32)
+		if err != nil {
+			return err
+		}
+
+		*id = BlockID(v)
+		return nil
+	}
+
+	var v uint32
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*id = BlockID(v)
+	return nil
+}
+
+// MarshalJSON emits id as a decimal JSON number. Use MarshalHex to emit the
+// "0x"-prefixed hexadecimal form Reassembly sometimes produces instead.
+func (id BlockID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uint32(id))
+}
+
+// MarshalHex returns id encoded as a "0x"-prefixed hexadecimal JSON string.
+func (id BlockID) MarshalHex() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("0x%x", uint32(id)))
+}
+
+// Color holds a ShipData color field. Reassembly stores it as a plain
+// JSON number or string, so Color carries the raw encoded value like
+// json.RawMessage, but also implements MarshalYAML/UnmarshalYAML so it
+// round-trips through a hand-written YAML fleet as a native scalar (e.g.
+// "color0: 16711680") instead of yaml.v3's default reflection over
+// []byte, which serializes as an unreadable per-byte integer list.
+type Color json.RawMessage
+
+// MarshalJSON returns c's encoded value as-is, matching
+// json.RawMessage.MarshalJSON.
+func (c Color) MarshalJSON() ([]byte, error) {
+	if len(c) == 0 {
+		return []byte("null"), nil
+	}
+
+	return c, nil
+}
+
+// UnmarshalJSON saves data as c's encoded value, matching
+// json.RawMessage.UnmarshalJSON.
+func (c *Color) UnmarshalJSON(data []byte) error {
+	*c = append((*c)[0:0], data...)
+	return nil
+}
+
+// MarshalYAML decodes c's JSON-encoded value into a generic number,
+// string, bool, or nil, so yaml.v3 emits it as a native scalar.
+func (c Color) MarshalYAML() (any, error) {
+	if len(c) == 0 {
+		return nil, nil
+	}
+
+	var v any
+	if err := json.Unmarshal(c, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// UnmarshalYAML accepts a YAML scalar (number, string, bool, or null) and
+// re-encodes it as JSON, so Color round-trips through either codec.
+func (c *Color) UnmarshalYAML(value *yaml.Node) error {
+	var v any
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	*c = Color(encoded)
+	return nil
 }
 
 // Defines the data field of a ship blueprint. This contains the ship's name,
 // author, colors, and wgroup setting.
 type ShipData struct {
 	// The name of the ship
-	Name   string          `json:"name"`
+	Name   string `json:"name" yaml:"name"`
 	// The name of the ship's creator
-	Author string          `json:"author"`
+	Author string `json:"author" yaml:"author"`
 	// Ship primary color
-	Color0 json.RawMessage `json:"color0,omitempty"`
+	Color0 Color  `json:"color0,omitempty" yaml:"color0,omitempty"`
 	// Ship secondary color
-	Color1 json.RawMessage `json:"color1,omitempty"`
+	Color1 Color  `json:"color1,omitempty" yaml:"color1,omitempty"`
 	// Ship tertiary color
-	Color2 json.RawMessage `json:"color2,omitempty"`
+	Color2 Color  `json:"color2,omitempty" yaml:"color2,omitempty"`
 	// Weapon binding group setting.
 	// Each index specifies whether the weapon group is set to "Fire All" or
 	// "Ripple Fire".
@@ -69,40 +229,215 @@ type ShipData struct {
 	// - if value is 2: set associated binding group to Ripple Fire (Fire
 	// weapons sequentially with the goal of achieving the maximum possible fire
 	// rate. Usually reduces fire rate significantly)
-	Wgroup [4]int          `json:"wgroup,omitempty"`
+	Wgroup [4]int          `json:"wgroup,omitempty" yaml:"wgroup,omitempty"`
 }
 
 // Defines a ship. Marhsal/unmarshal ship files with this datatype.
 type Ship struct {
 	// When imported into the sandbox, the ship will be positioned at this angle
 	// (in radians).
-	Angle    float64    `json:"angle,omitempty"`
+	Angle    float64    `json:"angle,omitempty" yaml:"angle,omitempty"`
 	// When imported into the sandbox, the ship may be positioned offset from
 	// the cursor by this vector (?)
-	Position [2]float64 `json:"position,omitempty"`
+	Position [2]float64 `json:"position,omitempty" yaml:"position,omitempty"`
 	// The ship's Data field as a ShipData type. This contains the ship's
 	// name, author, colors, and wgroup setting.
-	Data     ShipData   `json:"data"`
+	Data     ShipData   `json:"data" yaml:"data"`
 	// All blocks that comprise the ship. Stored as a Block slice.
-	Blocks   []Block    `json:"blocks"`
+	Blocks   []Block    `json:"blocks" yaml:"blocks"`
+	// Extra holds any top-level JSON keys this struct does not declare,
+	// captured at unmarshal time and re-emitted on marshal so that tools
+	// built on this library (editors, diff viewers) do not silently strip
+	// data introduced by newer save formats. Not preserved across YAML.
+	Extra         map[string]json.RawMessage `json:"-" yaml:"-"`
+	// SchemaVersion is the oldest SchemaVersion whose fields are all
+	// present in the JSON this ship was unmarshalled from. See Migrate.
+	SchemaVersion SchemaVersion              `json:"-" yaml:"-"`
+}
+
+var shipKnownKeys = []string{"angle", "position", "data", "blocks"}
+
+// UnmarshalJSON decodes data's known fields into s as usual, then
+// captures any keys it does not declare into s.Extra and tags s with the
+// SchemaVersion its source JSON implies.
+func (s *Ship) UnmarshalJSON(data []byte) error {
+	type alias Ship
+
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*s = Ship(a)
+
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for _, key := range shipKnownKeys {
+		delete(raw, key)
+	}
+	if len(raw) > 0 {
+		s.Extra = raw
+	}
+
+	s.SchemaVersion = detectShipSchemaVersion(data)
+
+	return nil
+}
+
+// MarshalJSON encodes s's known fields as usual, then merges in any keys
+// captured in s.Extra that a known field did not already claim.
+func (s Ship) MarshalJSON() ([]byte, error) {
+	type alias Ship
+
+	b, err := json.Marshal(alias(s))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Extra) == 0 {
+		return b, nil
+	}
+
+	return mergeExtra(b, s.Extra)
 }
 
 // Defines a fleet of multiple ships. This datatype is designed to be used in
 // Tournaments. Not intended to store fleets exported from campaign mode.
 type Fleet struct {
 	// List of ships that the fleet comprises, stored as Ship structures.
-	Blueprints []*Ship `json:"blueprints"`
+	Blueprints []*Ship `json:"blueprints" yaml:"blueprints"`
 	// Fleet primary color
-	Color0     any    `json:"color0,omitempty"`
+	Color0     any    `json:"color0,omitempty" yaml:"color0,omitempty"`
 	// Fleet secondary color
-	Color1     any    `json:"color1,omitempty"`
+	Color1     any    `json:"color1,omitempty" yaml:"color1,omitempty"`
 	// Fleet tertiary color
-	Color2     any    `json:"color2,omitempty"`
+	Color2     any    `json:"color2,omitempty" yaml:"color2,omitempty"`
 	// Faction number of fleet. All commands will be assigned to this faction on
 	// import into sandbox. Overwritten in Tournament mode.
-	Faction    int    `json:"faction"`
+	Faction    int    `json:"faction" yaml:"faction"`
 	// The name of the fleet
-	Name       string `json:"name"`
+	Name       string `json:"name" yaml:"name"`
+	// Extra holds any top-level JSON keys this struct does not declare.
+	// See Ship.Extra.
+	Extra      map[string]json.RawMessage `json:"-" yaml:"-"`
+}
+
+var fleetKnownKeys = []string{"blueprints", "color0", "color1", "color2", "faction", "name"}
+
+// UnmarshalJSON decodes data's known fields into f as usual, then
+// captures any keys it does not declare into f.Extra.
+func (f *Fleet) UnmarshalJSON(data []byte) error {
+	type alias Fleet
+
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*f = Fleet(a)
+
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for _, key := range fleetKnownKeys {
+		delete(raw, key)
+	}
+	if len(raw) > 0 {
+		f.Extra = raw
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes f's known fields as usual, then merges in any keys
+// captured in f.Extra that a known field did not already claim.
+func (f Fleet) MarshalJSON() ([]byte, error) {
+	type alias Fleet
+
+	b, err := json.Marshal(alias(f))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.Extra) == 0 {
+		return b, nil
+	}
+
+	return mergeExtra(b, f.Extra)
+}
+
+// mergeExtra decodes encoded as a JSON object and adds any key from extra
+// that it does not already contain, then re-encodes it.
+func mergeExtra(encoded []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	merged := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(encoded, &merged); err != nil {
+		return nil, err
+	}
+
+	for key, value := range extra {
+		if _, ok := merged[key]; !ok {
+			merged[key] = value
+		}
+	}
+
+	return json.Marshal(merged)
+}
+
+// SchemaVersion identifies a revision of Reassembly's save format that
+// this library understands.
+type SchemaVersion int
+
+const (
+	// SchemaV1 is the earliest save format this library supports.
+	SchemaV1 SchemaVersion = iota
+	// SchemaV2 introduced ShipData.Wgroup.
+	SchemaV2
+	// SchemaLatest is always the newest SchemaVersion this library knows
+	// about.
+	SchemaLatest = SchemaV2
+)
+
+// detectShipSchemaVersion inspects data for version-introduced keys (so
+// far, just ShipData.Wgroup's "wgroup") and returns the oldest
+// SchemaVersion whose fields are all present. It is best-effort: a ship
+// that doesn't use any version-introduced field is tagged SchemaV1.
+func detectShipSchemaVersion(data []byte) SchemaVersion {
+	var probe struct {
+		Data struct {
+			Wgroup json.RawMessage `json:"wgroup"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(data, &probe); err == nil && probe.Data.Wgroup != nil {
+		return SchemaV2
+	}
+
+	return SchemaV1
+}
+
+// Migrate returns a copy of ship adjusted to schema version to, applying
+// each intermediate version's migration steps in order. This library has
+// nothing to strip when migrating to an older version, since Reassembly
+// itself ignores fields it doesn't recognise; Migrate only updates the
+// tag in that direction.
+func Migrate(ship Ship, to SchemaVersion) (Ship, error) {
+	if to < SchemaV1 || to > SchemaLatest {
+		return Ship{}, fmt.Errorf("rsmships: unknown schema version %d", to)
+	}
+
+	for v := ship.SchemaVersion; v < to; v++ {
+		switch v {
+		case SchemaV1:
+			// V1 -> V2: introduced ShipData.Wgroup. Reassembly treats a
+			// missing wgroup identically to its default [0,0,0,0] value,
+			// so no field needs to change here.
+		}
+	}
+
+	ship.SchemaVersion = to
+
+	return ship, nil
 }
 
 // A dummy datatype to unmarshal data to to determine whether the data is a Ship
@@ -114,42 +449,182 @@ type UnprocessedShip struct {
 	Name json.RawMessage `json:"name"`
 }
 
-// Checks if the Reassembly JSON file at path is a fleet file
-func IsReassemblyJSONFileFleet(path string) (bool, error) {
-	content, err := os.ReadFile(path)
+// NewReader peeks the first two bytes of r and, if they match the gzip
+// magic number, transparently wraps r in a gzip.Reader.
+func NewReader(r io.Reader) io.Reader {
+	return codec.NewReader(r)
+}
+
+// DecodeShip decodes a ship from r, transparently decompressing gzip
+// content and sniffing JSON vs. YAML, since a reader has no file
+// extension to consult.
+func DecodeShip(r io.Reader) (Ship, error) {
+	var ship Ship
+
+	if err := codec.DecodeReader(r, &ship); err != nil {
+		return Ship{}, err
+	}
+
+	return ship.RemoveNilIds(), nil
+}
+
+// DecodeFleet decodes a fleet from r, transparently decompressing gzip
+// content and sniffing JSON vs. YAML, since a reader has no file
+// extension to consult.
+func DecodeFleet(r io.Reader) (Fleet, error) {
+	var fleet Fleet
+
+	if err := codec.DecodeReader(r, &fleet); err != nil {
+		return Fleet{}, err
+	}
+
+	return fleet, nil
+}
+
+// EncodeShip writes ship to w as JSON.
+func EncodeShip(w io.Writer, s Ship) error {
+	return codec.EncodeWriter(w, s, SaveOptions{Format: codec.FormatJSON})
+}
+
+// EncodeShipHex writes ship to w as JSON, the same as EncodeShip, except
+// every block's "ident" is emitted via BlockID.MarshalHex as a
+// "0x"-prefixed hex string rather than as a decimal number, matching the
+// other form Reassembly sometimes produces.
+func EncodeShipHex(w io.Writer, s Ship) error {
+	b, err := marshalShipHexIds(s)
 	if err != nil {
-		return false, err
+		return err
 	}
 
+	return codec.WriteTo(w, b, false)
+}
+
+// SaveShipHex writes ship to path as JSON, gzip-compressed when path ends
+// in ".gz" (mirroring SaveShip), except every block's "ident" is emitted
+// via BlockID.MarshalHex rather than as a decimal number.
+func SaveShipHex(path string, ship Ship) error {
+	b, err := marshalShipHexIds(ship)
+	if err != nil {
+		return err
+	}
+
+	_, gzipped := codec.DetectFormat(path)
+	return codec.WriteFile(path, b, gzipped)
+}
+
+// hexBlockID marshals like BlockID, but via MarshalHex instead of the
+// decimal default. It backs EncodeShipHex and SaveShipHex.
+type hexBlockID BlockID
+
+func (id hexBlockID) MarshalJSON() ([]byte, error) {
+	return BlockID(id).MarshalHex()
+}
+
+// hexBlock mirrors Block, but marshals Id through hexBlockID.
+type hexBlock struct {
+	Id        *hexBlockID  `json:"ident" yaml:"ident"`
+	Offset    [2]float64   `json:"offset" yaml:"offset"`
+	Angle     float64      `json:"angle" yaml:"angle"`
+	Command   *CommandData `json:"command,omitempty" yaml:"command,omitempty"`
+	BindingId int          `json:"bindingId,omitempty" yaml:"bindingId,omitempty"`
+}
+
+// hexShip mirrors Ship, but marshals its Blocks through hexBlock.
+type hexShip struct {
+	Angle    float64    `json:"angle,omitempty" yaml:"angle,omitempty"`
+	Position [2]float64 `json:"position,omitempty" yaml:"position,omitempty"`
+	Data     ShipData   `json:"data" yaml:"data"`
+	Blocks   []hexBlock `json:"blocks" yaml:"blocks"`
+}
+
+// marshalShipHexIds marshals ship as JSON with every block's Id emitted
+// via BlockID.MarshalHex, preserving ship.Extra the same way
+// Ship.MarshalJSON does.
+func marshalShipHexIds(ship Ship) ([]byte, error) {
+	hs := hexShip{Angle: ship.Angle, Position: ship.Position, Data: ship.Data}
+	hs.Blocks = make([]hexBlock, len(ship.Blocks))
+	for i, b := range ship.Blocks {
+		var id *hexBlockID
+		if b.Id != nil {
+			h := hexBlockID(*b.Id)
+			id = &h
+		}
+
+		hs.Blocks[i] = hexBlock{Id: id, Offset: b.Offset, Angle: b.Angle, Command: b.Command, BindingId: b.BindingId}
+	}
+
+	b, err := json.Marshal(hs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ship.Extra) == 0 {
+		return b, nil
+	}
+
+	return mergeExtra(b, ship.Extra)
+}
+
+// EncodeFleet writes fleet to w as JSON.
+func EncodeFleet(w io.Writer, f Fleet) error {
+	return codec.EncodeWriter(w, f, SaveOptions{Format: codec.FormatJSON})
+}
+
+// IsReassemblyJSONFleet reports whether r contains fleet JSON (i.e. has a
+// top-level "name" field), so that callers who already have the content
+// in hand do not need to re-read it from disk.
+func IsReassemblyJSONFleet(r io.Reader) (bool, error) {
 	var idk UnprocessedShip
 
-	if err := json.Unmarshal([]byte(content), &idk); err != nil {
+	if err := codec.DecodeReader(r, &idk); err != nil {
 		return false, err
 	}
 
-	if idk.Name == nil {
-		return false, nil
-	} else {
-		return true, nil
-	}
+	return idk.Name != nil, nil
 }
 
-// Unmarshals a ship file at path to a Ship structure.
-func UnmarshalShipFromFile(path string) (Ship, error) {
-	content, err := os.ReadFile(path)
+// Checks if the Reassembly JSON file at path is a fleet file
+func IsReassemblyJSONFileFleet(path string) (bool, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return Ship{}, err
+		return false, err
 	}
+	defer file.Close()
 
+	return IsReassemblyJSONFleet(file)
+}
+
+// SaveOptions controls how SaveShip and SaveFleet write files. See
+// codec.SaveOptions for details.
+type SaveOptions = codec.SaveOptions
+
+// LoadShip loads a ship from path. The codec is chosen from the file
+// extension (".json", ".json.gz", ".yaml", ".yml", ".yaml.gz"); if the
+// extension is missing or unrecognised, the content is sniffed instead.
+func LoadShip(path string) (Ship, error) {
 	var ship Ship
 
-	if err := json.Unmarshal([]byte(content), &ship); err != nil {
+	if err := codec.Decode(path, &ship); err != nil {
 		return Ship{}, err
 	}
 
 	return ship.RemoveNilIds(), nil
 }
 
+// SaveShip writes ship to path using the codec selected by opts, falling
+// back to the destination path's extension when opts.Format is left as
+// codec.FormatUnknown.
+func SaveShip(path string, ship Ship, opts SaveOptions) error {
+	return codec.Encode(path, ship, opts)
+}
+
+// Unmarshals a ship file at path to a Ship structure.
+//
+// Deprecated: use LoadShip instead.
+func UnmarshalShipFromFile(path string) (Ship, error) {
+	return LoadShip(path)
+}
+
 // Remove all blocks with Nil or undefined block IDs. In Reassembly, an example
 // of a block with a nil ID are the useless square-shaped sometimes-launchable
 // blocks on a ship left near or on launchers after exiting and re-entering the
@@ -179,70 +654,53 @@ func (ship *Ship) RemoveNilIds() Ship {
 }
 
 // Marshal Ship ship to file at path. Should use ".json" file extension.
+//
+// Deprecated: use SaveShip instead.
 func MarshalShipToFile(path string, ship Ship) error {
-	b, err := json.Marshal(ship)
-	if err != nil {
-		return err
-	}
-
-	if err := os.WriteFile(path, b, 0666); err != nil {
-		return err
-	}
-
-	return nil
+	return SaveShip(path, ship, SaveOptions{Format: codec.FormatJSON})
 }
 
-func UnmarshalFleetFromFile(path string) (Fleet, error) {
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return Fleet{}, err
-	}
-
+// LoadFleet loads a fleet from path. The codec is chosen from the file
+// extension (".json", ".json.gz", ".yaml", ".yml", ".yaml.gz"); if the
+// extension is missing or unrecognised, the content is sniffed instead.
+func LoadFleet(path string) (Fleet, error) {
 	var fleet Fleet
 
-	if err := json.Unmarshal([]byte(content), &fleet); err != nil {
+	if err := codec.Decode(path, &fleet); err != nil {
 		return Fleet{}, err
 	}
 
 	return fleet, nil
 }
 
+// SaveFleet writes fleet to path using the codec selected by opts, falling
+// back to the destination path's extension when opts.Format is left as
+// codec.FormatUnknown.
+func SaveFleet(path string, fleet Fleet, opts SaveOptions) error {
+	return codec.Encode(path, fleet, opts)
+}
+
+// Deprecated: use LoadFleet instead.
+func UnmarshalFleetFromFile(path string) (Fleet, error) {
+	return LoadFleet(path)
+}
+
 // Marshal Fleet fleet to gzip-compressed JSON file at path. Should use
 // ".json.gz" file extension.
-
+//
 // Note that although Reassembly does not save gzipped JSON fleet files when
 // cvar kWriteJSON is set to 1, it can still read them.
+//
+// Deprecated: use SaveFleet instead.
 func MarshalFleetToFileGzip(path string, fleet Fleet) error {
-	b, err := json.Marshal(fleet)
-	if err != nil {
-		return err
-	}
-
-	file, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	gz, _ := gzip.NewWriterLevel(file, gzip.BestCompression)
-	gz.Write(b)
-	defer gz.Close()
-
-	return nil
+	return SaveFleet(path, fleet, SaveOptions{Format: codec.FormatJSON, Gzip: true})
 }
 
 // Marshal Fleet fleet to JSON file at path. Should use ".json" file extension.
+//
+// Deprecated: use SaveFleet instead.
 func MarshalFleetToFile(path string, fleet Fleet) error {
-	b, err := json.Marshal(fleet)
-	if err != nil {
-		return err
-	}
-
-	if err := os.WriteFile(path, b, 0666); err != nil {
-		return err
-	}
-
-	return nil
+	return SaveFleet(path, fleet, SaveOptions{Format: codec.FormatJSON})
 }
 
 // Return a copy of the Fleet with ships
@@ -255,6 +713,7 @@ func (f *Fleet) CopyUsingShips(ships []*Ship) Fleet {
 		Faction: f.Faction,
 		Name: f.Name,
 		Blueprints: ships,
+		Extra: f.Extra,
 	}
 }
 
@@ -0,0 +1,206 @@
+package rsmships
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// A block with "ident": 0 is a real, present block ID (Reassembly does
+// hand out 0 as a valid ID), distinct from a block whose "ident" is
+// missing or null entirely. RemoveNilIds must only drop the latter.
+func TestRemoveNilIdsKeepsIdentZero(t *testing.T) {
+	data := []byte(`{
+		"angle": 0,
+		"position": [0, 0],
+		"data": {"name": "test", "author": "test"},
+		"blocks": [
+			{"ident": 0, "offset": [0, 0], "angle": 0},
+			{"offset": [1, 0], "angle": 0},
+			{"ident": null, "offset": [2, 0], "angle": 0},
+			{"ident": 5, "offset": [3, 0], "angle": 0}
+		]
+	}`)
+
+	var ship Ship
+	if err := ship.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+
+	got := ship.RemoveNilIds()
+	if len(got.Blocks) != 2 {
+		t.Fatalf("RemoveNilIds: got %d blocks, want 2 (ident 0 and ident 5): %+v", len(got.Blocks), got.Blocks)
+	}
+
+	if got.Blocks[0].Id == nil || *got.Blocks[0].Id != 0 {
+		t.Errorf("RemoveNilIds: first surviving block should have ident 0, got %+v", got.Blocks[0].Id)
+	}
+	if got.Blocks[1].Id == nil || *got.Blocks[1].Id != 5 {
+		t.Errorf("RemoveNilIds: second surviving block should have ident 5, got %+v", got.Blocks[1].Id)
+	}
+}
+
+func TestEncodeShipHexEmitsHexIdents(t *testing.T) {
+	id := BlockID(255)
+	ship := Ship{
+		Data: ShipData{Name: "test", Author: "test"},
+		Blocks: []Block{
+			{Id: &id, Offset: [2]float64{1, 2}, Angle: 0},
+			{Offset: [2]float64{3, 4}, Angle: 0},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeShipHex(&buf, ship); err != nil {
+		t.Fatalf("EncodeShipHex: %v", err)
+	}
+
+	var decoded struct {
+		Blocks []struct {
+			Id any `json:"ident"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unmarshal EncodeShipHex output: %v", err)
+	}
+
+	if decoded.Blocks[0].Id != "0xff" {
+		t.Errorf("EncodeShipHex: first block ident = %v, want \"0xff\"", decoded.Blocks[0].Id)
+	}
+	if decoded.Blocks[1].Id != nil {
+		t.Errorf("EncodeShipHex: second block ident = %v, want nil", decoded.Blocks[1].Id)
+	}
+
+	var ship2 Ship
+	if err := ship2.UnmarshalJSON(buf.Bytes()); err != nil {
+		t.Fatalf("round-trip UnmarshalJSON: %v", err)
+	}
+	if ship2.Blocks[0].Id == nil || *ship2.Blocks[0].Id != 255 {
+		t.Errorf("round-trip: first block ident = %+v, want 255", ship2.Blocks[0].Id)
+	}
+}
+
+func TestCommandFlagsMarshalsBareStringForOneFlag(t *testing.T) {
+	b, err := json.Marshal(CommandFlags{FlagAIBinding})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(b) != `"AI_BINDING"` {
+		t.Errorf("Marshal(single flag) = %s, want a bare string", b)
+	}
+
+	b, err = json.Marshal(CommandFlags{FlagAIBinding, FlagAlwaysKite})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(b) != `["AI_BINDING","ALWAYS_KITE"]` {
+		t.Errorf("Marshal(two flags) = %s, want an array", b)
+	}
+}
+
+func TestCommandFlagsUnmarshalsBothForms(t *testing.T) {
+	var bare CommandFlags
+	if err := json.Unmarshal([]byte(`"AI_BINDING"`), &bare); err != nil {
+		t.Fatalf("Unmarshal(bare string): %v", err)
+	}
+	if len(bare) != 1 || bare[0] != FlagAIBinding {
+		t.Errorf("Unmarshal(bare string) = %v, want [AI_BINDING]", bare)
+	}
+
+	var array CommandFlags
+	if err := json.Unmarshal([]byte(`["AI_BINDING","ALWAYS_KITE"]`), &array); err != nil {
+		t.Fatalf("Unmarshal(array): %v", err)
+	}
+	if len(array) != 2 || array[0] != FlagAIBinding || array[1] != FlagAlwaysKite {
+		t.Errorf("Unmarshal(array) = %v, want [AI_BINDING ALWAYS_KITE]", array)
+	}
+}
+
+func TestBlockIDUnmarshalsDecimalAndHex(t *testing.T) {
+	var decimal BlockID
+	if err := json.Unmarshal([]byte(`255`), &decimal); err != nil {
+		t.Fatalf("Unmarshal(decimal): %v", err)
+	}
+	if decimal != 255 {
+		t.Errorf("Unmarshal(decimal) = %d, want 255", decimal)
+	}
+
+	var hex BlockID
+	if err := json.Unmarshal([]byte(`"0xff"`), &hex); err != nil {
+		t.Fatalf("Unmarshal(hex): %v", err)
+	}
+	if hex != 255 {
+		t.Errorf("Unmarshal(hex) = %d, want 255", hex)
+	}
+}
+
+// Keys this package does not declare (here, a hypothetical future
+// "tag" field) must survive an unmarshal/marshal round trip unchanged.
+func TestShipExtraRoundTrips(t *testing.T) {
+	data := []byte(`{
+		"angle": 0,
+		"position": [0, 0],
+		"data": {"name": "test", "author": "test"},
+		"blocks": [],
+		"tag": "unreleased-field"
+	}`)
+
+	var ship Ship
+	if err := ship.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	if string(ship.Extra["tag"]) != `"unreleased-field"` {
+		t.Fatalf("Extra[tag] = %s, want %q", ship.Extra["tag"], `"unreleased-field"`)
+	}
+
+	out, err := ship.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+
+	var roundTripped map[string]json.RawMessage
+	if err := json.Unmarshal(out, &roundTripped); err != nil {
+		t.Fatalf("unmarshal round-tripped output: %v", err)
+	}
+	if string(roundTripped["tag"]) != `"unreleased-field"` {
+		t.Errorf("round-tripped tag = %s, want %q", roundTripped["tag"], `"unreleased-field"`)
+	}
+}
+
+// LoadShip must accept a YAML fleet a modder hand-wrote, not just one
+// round-tripped through SaveShip itself: a color set as a plain YAML
+// scalar (number or string) must decode cleanly rather than erroring out
+// because ShipData.Color0's underlying type has no YAML handling.
+func TestLoadShipYAMLHandWrittenColors(t *testing.T) {
+	const handWritten = `angle: 0
+position: [0, 0]
+data:
+  name: test
+  author: test
+  color0: 16711680
+  color1: "65280"
+blocks:
+  - ident: 1
+    offset: [0, 0]
+    angle: 0
+`
+
+	path := filepath.Join(t.TempDir(), "ship.yaml")
+	if err := os.WriteFile(path, []byte(handWritten), 0644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	ship, err := LoadShip(path)
+	if err != nil {
+		t.Fatalf("LoadShip: %v", err)
+	}
+
+	if string(ship.Data.Color0) != "16711680" {
+		t.Errorf("Data.Color0 = %s, want 16711680", ship.Data.Color0)
+	}
+	if string(ship.Data.Color1) != `"65280"` {
+		t.Errorf("Data.Color1 = %s, want %q", ship.Data.Color1, `"65280"`)
+	}
+}
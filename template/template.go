@@ -0,0 +1,411 @@
+// Package template lets a tournament organiser author a fleet template —
+// a Fleet whose string fields may contain Go text/template actions — and
+// stamp out concrete variants from it without hand-editing each ship.
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/SomeDebris/rsmships-go"
+)
+
+// ShipVars is the per-ship metadata made available to a template action
+// alongside the caller-supplied vars, under the "Ship" key (e.g.
+// "{{.Ship.Index}}").
+type ShipVars struct {
+	// Index is the ship's position within Fleet.Blueprints.
+	Index int
+	// Total is the number of ships in the fleet.
+	Total int
+	// BlockCount is len(ship.Blocks).
+	BlockCount int
+	// Centroid is the mean of every block's Offset in the ship.
+	Centroid [2]float64
+}
+
+// RenderFleet copies tmpl and evaluates every string field that may
+// carry a Go text/template action (Name, Data.Name, Data.Author) against
+// vars plus that ship's ShipVars. The loosely-typed color fields
+// (Fleet.Color0-2, ShipData.Color0-2) are rendered the same way when they
+// hold a string, and re-parsed as a JSON number if the rendered text
+// looks like one.
+//
+// The copy is shallow below the ship level: the returned fleet gets its
+// own Blueprints slice, Ship values and Blocks slices, but each block's
+// Id and Command pointers, any color value left unrendered, and the
+// Extra maps are shared with tmpl.
+//
+// Faction and Angle cannot be templated through this entry point: they
+// are concrete Go int/float64 fields with no room left to carry template
+// source text once a Fleet has already been unmarshalled. To template
+// those too, author the fleet as a JSON file and use RenderFleetJSON,
+// which walks the raw JSON tree before Fleet's strict types are applied.
+func RenderFleet(tmpl rsmships.Fleet, vars map[string]any) (rsmships.Fleet, error) {
+	out := cloneFleet(tmpl)
+
+	var err error
+	if out.Name, err = renderString(out.Name, vars, nil); err != nil {
+		return rsmships.Fleet{}, fmt.Errorf("fleet name: %w", err)
+	}
+	if out.Color0, err = renderColor(out.Color0, vars, nil); err != nil {
+		return rsmships.Fleet{}, fmt.Errorf("fleet color0: %w", err)
+	}
+	if out.Color1, err = renderColor(out.Color1, vars, nil); err != nil {
+		return rsmships.Fleet{}, fmt.Errorf("fleet color1: %w", err)
+	}
+	if out.Color2, err = renderColor(out.Color2, vars, nil); err != nil {
+		return rsmships.Fleet{}, fmt.Errorf("fleet color2: %w", err)
+	}
+
+	total := len(out.Blueprints)
+	for i, ship := range out.Blueprints {
+		if ship == nil {
+			continue
+		}
+
+		sv := ShipVars{
+			Index:      i,
+			Total:      total,
+			BlockCount: len(ship.Blocks),
+			Centroid:   centroid(ship.Blocks),
+		}
+
+		if err := renderShip(ship, vars, sv); err != nil {
+			return rsmships.Fleet{}, fmt.Errorf("ship %d: %w", i, err)
+		}
+	}
+
+	return out, nil
+}
+
+// RenderFleetJSON parses src as a JSON fleet template and renders it
+// against vars, the same as RenderFleet, except Faction and Angle may
+// also carry Go text/template actions (e.g. "faction": "{{.faction}}").
+// src is walked as a raw JSON tree before being decoded into an
+// rsmships.Fleet, so those fields can hold template source text even
+// though rsmships.Fleet.Faction and rsmships.Ship.Angle are typed
+// int/float64 fields that could never hold it once decoded.
+func RenderFleetJSON(src []byte, vars map[string]any) (rsmships.Fleet, error) {
+	var tree any
+	if err := json.Unmarshal(src, &tree); err != nil {
+		return rsmships.Fleet{}, err
+	}
+
+	root, ok := tree.(map[string]any)
+	if !ok {
+		return rsmships.Fleet{}, fmt.Errorf("template: fleet template must be a JSON object")
+	}
+
+	if err := renderFleetTree(root, vars); err != nil {
+		return rsmships.Fleet{}, err
+	}
+
+	rendered, err := json.Marshal(root)
+	if err != nil {
+		return rsmships.Fleet{}, err
+	}
+
+	var fleet rsmships.Fleet
+	if err := json.Unmarshal(rendered, &fleet); err != nil {
+		return rsmships.Fleet{}, err
+	}
+
+	return fleet, nil
+}
+
+// renderFleetTree renders a fleet template's raw JSON tree in place,
+// mirroring RenderFleet but reaching "faction" and "angle" too, since at
+// this stage they are still untyped and may hold template source text.
+func renderFleetTree(root map[string]any, vars map[string]any) error {
+	if err := renderStringFields(root, vars, nil, "name"); err != nil {
+		return err
+	}
+	if err := renderColorFields(root, vars, nil); err != nil {
+		return err
+	}
+	if err := renderNumericField(root, "faction", vars, nil); err != nil {
+		return err
+	}
+
+	blueprints, _ := root["blueprints"].([]any)
+	total := len(blueprints)
+
+	for i, raw := range blueprints {
+		ship, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		sv := ShipVars{
+			Index:      i,
+			Total:      total,
+			BlockCount: shipBlockCount(ship),
+			Centroid:   shipCentroid(ship),
+		}
+
+		if err := renderShipTree(ship, vars, sv); err != nil {
+			return fmt.Errorf("ship %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func renderShipTree(ship map[string]any, vars map[string]any, sv ShipVars) error {
+	if err := renderNumericField(ship, "angle", vars, sv); err != nil {
+		return err
+	}
+
+	data, _ := ship["data"].(map[string]any)
+	if data == nil {
+		return nil
+	}
+
+	if err := renderStringFields(data, vars, sv, "name", "author"); err != nil {
+		return err
+	}
+
+	return renderColorFields(data, vars, sv)
+}
+
+func renderStringFields(m map[string]any, vars map[string]any, sv any, keys ...string) error {
+	for _, key := range keys {
+		s, ok := m[key].(string)
+		if !ok {
+			continue
+		}
+
+		rendered, err := renderString(s, vars, sv)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		m[key] = rendered
+	}
+
+	return nil
+}
+
+func renderColorFields(m map[string]any, vars map[string]any, sv any) error {
+	for _, key := range []string{"color0", "color1", "color2"} {
+		v, ok := m[key]
+		if !ok {
+			continue
+		}
+
+		rendered, err := renderColor(v, vars, sv)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+
+		m[key] = rendered
+	}
+
+	return nil
+}
+
+// renderNumericField renders m[key] as a template when it holds a
+// string, then re-parses the result as a JSON number in place, since
+// key's eventual Go field (Fleet.Faction, Ship.Angle) is numeric. A
+// value that already holds a number is left untouched.
+func renderNumericField(m map[string]any, key string, vars map[string]any, sv any) error {
+	s, ok := m[key].(string)
+	if !ok {
+		return nil
+	}
+
+	rendered, err := renderString(s, vars, sv)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	n, err := strconv.ParseFloat(rendered, 64)
+	if err != nil {
+		return fmt.Errorf("%s: rendered value %q is not a number", key, rendered)
+	}
+
+	m[key] = n
+	return nil
+}
+
+// shipBlockCount and shipCentroid compute ShipVars.BlockCount and
+// ShipVars.Centroid directly from a ship's raw JSON tree, mirroring
+// centroid, since RenderFleetJSON renders Angle before the tree is
+// decoded into typed rsmships.Block values.
+func shipBlockCount(ship map[string]any) int {
+	blocks, _ := ship["blocks"].([]any)
+	return len(blocks)
+}
+
+func shipCentroid(ship map[string]any) [2]float64 {
+	blocks, _ := ship["blocks"].([]any)
+	if len(blocks) == 0 {
+		return [2]float64{}
+	}
+
+	var sum [2]float64
+	for _, raw := range blocks {
+		block, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		offset, ok := block["offset"].([]any)
+		if !ok || len(offset) != 2 {
+			continue
+		}
+
+		x, _ := offset[0].(float64)
+		y, _ := offset[1].(float64)
+		sum[0] += x
+		sum[1] += y
+	}
+
+	n := float64(len(blocks))
+	return [2]float64{sum[0] / n, sum[1] / n}
+}
+
+func renderShip(ship *rsmships.Ship, vars map[string]any, sv ShipVars) error {
+	var err error
+
+	if ship.Data.Name, err = renderString(ship.Data.Name, vars, sv); err != nil {
+		return fmt.Errorf("data.name: %w", err)
+	}
+	if ship.Data.Author, err = renderString(ship.Data.Author, vars, sv); err != nil {
+		return fmt.Errorf("data.author: %w", err)
+	}
+	if ship.Data.Color0, err = renderRawColor(ship.Data.Color0, vars, sv); err != nil {
+		return fmt.Errorf("data.color0: %w", err)
+	}
+	if ship.Data.Color1, err = renderRawColor(ship.Data.Color1, vars, sv); err != nil {
+		return fmt.Errorf("data.color1: %w", err)
+	}
+	if ship.Data.Color2, err = renderRawColor(ship.Data.Color2, vars, sv); err != nil {
+		return fmt.Errorf("data.color2: %w", err)
+	}
+
+	return nil
+}
+
+// templateData merges the caller's vars with the current ship's
+// metadata, exposed under the "Ship" key.
+func templateData(vars map[string]any, sv any) map[string]any {
+	data := make(map[string]any, len(vars)+1)
+	for k, v := range vars {
+		data[k] = v
+	}
+	if sv != nil {
+		data["Ship"] = sv
+	}
+
+	return data
+}
+
+func renderString(src string, vars map[string]any, sv any) (string, error) {
+	if !strings.Contains(src, "{{") {
+		return src, nil
+	}
+
+	t, err := template.New("rsmships").Parse(src)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, templateData(vars, sv)); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// renderColor re-renders a Fleet color field, which is stored as `any`
+// and typically holds either a string, a number, or nil. Strings are run
+// through the template engine and re-parsed as a JSON number if the
+// rendered text looks like one, so e.g. "{{.hue}}" can expand to a color
+// value rather than staying a string.
+func renderColor(v any, vars map[string]any, sv any) (any, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+
+	rendered, err := renderString(s, vars, sv)
+	if err != nil {
+		return nil, err
+	}
+
+	if n, err := strconv.ParseFloat(rendered, 64); err == nil {
+		return n, nil
+	}
+
+	return rendered, nil
+}
+
+// renderRawColor is renderColor's counterpart for ShipData's color
+// fields, which are stored as rsmships.Color rather than `any`.
+func renderRawColor(raw rsmships.Color, vars map[string]any, sv any) (rsmships.Color, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		// Not a JSON string (e.g. already a number), so there is nothing
+		// to template.
+		return raw, nil
+	}
+
+	rendered, err := renderString(s, vars, sv)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := strconv.ParseFloat(rendered, 64); err == nil {
+		return rsmships.Color(rendered), nil
+	}
+
+	encoded, err := json.Marshal(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	return rsmships.Color(encoded), nil
+}
+
+func centroid(blocks []rsmships.Block) [2]float64 {
+	if len(blocks) == 0 {
+		return [2]float64{}
+	}
+
+	var sum [2]float64
+	for _, b := range blocks {
+		sum[0] += b.Offset[0]
+		sum[1] += b.Offset[1]
+	}
+
+	n := float64(len(blocks))
+	return [2]float64{sum[0] / n, sum[1] / n}
+}
+
+func cloneFleet(f rsmships.Fleet) rsmships.Fleet {
+	out := f
+	out.Blueprints = make([]*rsmships.Ship, len(f.Blueprints))
+
+	for i, ship := range f.Blueprints {
+		if ship == nil {
+			continue
+		}
+
+		clone := *ship
+		clone.Blocks = append([]rsmships.Block(nil), ship.Blocks...)
+		out.Blueprints[i] = &clone
+	}
+
+	return out
+}
@@ -0,0 +1,63 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/SomeDebris/rsmships-go"
+)
+
+func TestRenderFleetTemplatesStrings(t *testing.T) {
+	tmpl := rsmships.Fleet{Name: "{{.name}}"}
+
+	fleet, err := RenderFleet(tmpl, map[string]any{"name": "Reds"})
+	if err != nil {
+		t.Fatalf("RenderFleet: %v", err)
+	}
+
+	if fleet.Name != "Reds" {
+		t.Errorf("Name = %q, want %q", fleet.Name, "Reds")
+	}
+}
+
+func TestRenderFleetJSONTemplatesFactionAndAngle(t *testing.T) {
+	src := []byte(`{
+		"name": "{{.fleetName}}",
+		"faction": "{{.faction}}",
+		"blueprints": [
+			{
+				"angle": "{{.angle}}",
+				"data": {"name": "ship {{.Ship.Index}}", "author": "test"},
+				"blocks": []
+			}
+		]
+	}`)
+
+	fleet, err := RenderFleetJSON(src, map[string]any{"fleetName": "Reds", "faction": 101, "angle": 1.5})
+	if err != nil {
+		t.Fatalf("RenderFleetJSON: %v", err)
+	}
+
+	if fleet.Name != "Reds" {
+		t.Errorf("Name = %q, want %q", fleet.Name, "Reds")
+	}
+	if fleet.Faction != 101 {
+		t.Errorf("Faction = %d, want 101", fleet.Faction)
+	}
+	if len(fleet.Blueprints) != 1 {
+		t.Fatalf("Blueprints = %d, want 1", len(fleet.Blueprints))
+	}
+	if fleet.Blueprints[0].Angle != 1.5 {
+		t.Errorf("Angle = %v, want 1.5", fleet.Blueprints[0].Angle)
+	}
+	if fleet.Blueprints[0].Data.Name != "ship 0" {
+		t.Errorf("Data.Name = %q, want %q", fleet.Blueprints[0].Data.Name, "ship 0")
+	}
+}
+
+func TestRenderFleetJSONRejectsNonNumericAngle(t *testing.T) {
+	src := []byte(`{"name": "f", "faction": 0, "blueprints": [{"angle": "{{.angle}}", "data": {"name": "n", "author": "a"}, "blocks": []}]}`)
+
+	if _, err := RenderFleetJSON(src, map[string]any{"angle": "not-a-number"}); err == nil {
+		t.Error("RenderFleetJSON: expected an error for a non-numeric rendered angle, got nil")
+	}
+}
@@ -0,0 +1,288 @@
+// Package tournament validates ships and fleets against configurable
+// tournament rulesets before they are imported into the game. Unlike
+// rsmships.Block's unmarshalling (which silently purges block fields it
+// doesn't declare), Lint never mutates its input: it reports
+// every violation as a Diagnostic and leaves the decision of what to do
+// about it to the caller.
+package tournament
+
+import (
+	"fmt"
+
+	"github.com/SomeDebris/rsmships-go"
+	"github.com/SomeDebris/rsmships-go/codec"
+)
+
+// Severity classifies how serious a Diagnostic is.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic reports a single ruleset violation found by Lint.
+type Diagnostic struct {
+	Severity Severity
+	// ShipIndex is the index of the offending ship within
+	// Fleet.Blueprints, or -1 if the diagnostic applies to the fleet as a
+	// whole.
+	ShipIndex int
+	Message   string
+}
+
+func (d Diagnostic) String() string {
+	if d.ShipIndex < 0 {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+
+	return fmt.Sprintf("%s: ship %d: %s", d.Severity, d.ShipIndex, d.Message)
+}
+
+// BlockRule caps how many of a given block ID a ship may contain, or bans
+// it outright.
+type BlockRule struct {
+	MaxCount int  `json:"maxCount,omitempty" yaml:"maxCount,omitempty"`
+	Banned   bool `json:"banned,omitempty" yaml:"banned,omitempty"`
+}
+
+// Ruleset declares the constraints a fleet must satisfy to be imported
+// into a tournament.
+type Ruleset struct {
+	// Name identifies the ruleset, e.g. the tournament it belongs to.
+	Name string `json:"name" yaml:"name"`
+	// BlockRules caps or bans individual block IDs.
+	BlockRules map[rsmships.BlockID]BlockRule `json:"blockRules,omitempty" yaml:"blockRules,omitempty"`
+	// BlockValues assigns a point (P-value) cost to each block ID, summed
+	// across a fleet to enforce MaxFleetValue.
+	BlockValues map[rsmships.BlockID]float64 `json:"blockValues,omitempty" yaml:"blockValues,omitempty"`
+	// MaxFleetValue caps the fleet's total BlockValues. Zero means
+	// unbounded.
+	MaxFleetValue float64 `json:"maxFleetValue,omitempty" yaml:"maxFleetValue,omitempty"`
+	// AllowedFlags restricts which CommandFlags a ship's blocks may set.
+	// A nil slice means all flags are allowed.
+	AllowedFlags []rsmships.CommandFlag `json:"allowedFlags,omitempty" yaml:"allowedFlags,omitempty"`
+	// RequireBindingDAG rejects AI_BINDING chains that form a cycle.
+	RequireBindingDAG bool `json:"requireBindingDag,omitempty" yaml:"requireBindingDag,omitempty"`
+	// MaxNameLength and MaxAuthorLength cap Ship.Data.Name and
+	// Ship.Data.Author, matching the game's engine limits. Zero means
+	// unbounded.
+	MaxNameLength   int `json:"maxNameLength,omitempty" yaml:"maxNameLength,omitempty"`
+	MaxAuthorLength int `json:"maxAuthorLength,omitempty" yaml:"maxAuthorLength,omitempty"`
+	// Factions lists the faction numbers tournament mode normalises
+	// fleets to, e.g. [100, 101, 102]. A nil slice allows any faction.
+	Factions []int `json:"factions,omitempty" yaml:"factions,omitempty"`
+}
+
+// LoadRuleset loads a ruleset from path, selecting a JSON or YAML decoder
+// by file extension and falling back to content sniffing, mirroring
+// rsmships.LoadFleet.
+func LoadRuleset(path string) (Ruleset, error) {
+	var rules Ruleset
+
+	if err := codec.Decode(path, &rules); err != nil {
+		return Ruleset{}, err
+	}
+
+	return rules, nil
+}
+
+// Lint walks every ship in fleet and reports every violation of rules as a
+// Diagnostic. It never mutates fleet.
+func Lint(fleet rsmships.Fleet, rules Ruleset) []Diagnostic {
+	var diags []Diagnostic
+
+	if len(rules.Factions) > 0 && !containsInt(rules.Factions, fleet.Faction) {
+		diags = append(diags, Diagnostic{
+			ShipIndex: -1,
+			Message:   fmt.Sprintf("fleet faction %d is not one of the tournament's allowed factions %v", fleet.Faction, rules.Factions),
+		})
+	}
+
+	var fleetValue float64
+	seen := make(map[string]int, len(fleet.Blueprints))
+
+	for i, ship := range fleet.Blueprints {
+		if ship == nil {
+			diags = append(diags, Diagnostic{ShipIndex: i, Message: "blueprint is nil"})
+			continue
+		}
+
+		diags = append(diags, lintShip(i, ship, rules, &fleetValue, seen)...)
+	}
+
+	if rules.MaxFleetValue > 0 && fleetValue > rules.MaxFleetValue {
+		diags = append(diags, Diagnostic{
+			ShipIndex: -1,
+			Message:   fmt.Sprintf("fleet value %.1f exceeds the tournament budget of %.1f", fleetValue, rules.MaxFleetValue),
+		})
+	}
+
+	return diags
+}
+
+func lintShip(i int, ship *rsmships.Ship, rules Ruleset, fleetValue *float64, seen map[string]int) []Diagnostic {
+	var diags []Diagnostic
+
+	key := ship.Data.Name + "\x00" + ship.Data.Author
+	if dupIdx, ok := seen[key]; ok {
+		diags = append(diags, Diagnostic{
+			Severity:  SeverityWarning,
+			ShipIndex: i,
+			Message:   fmt.Sprintf("duplicate of ship %d (%q by %q)", dupIdx, ship.Data.Name, ship.Data.Author),
+		})
+	} else {
+		seen[key] = i
+	}
+
+	if rules.MaxNameLength > 0 && len(ship.Data.Name) > rules.MaxNameLength {
+		diags = append(diags, Diagnostic{ShipIndex: i, Message: fmt.Sprintf("name %q exceeds the %d character engine limit", ship.Data.Name, rules.MaxNameLength)})
+	}
+	if rules.MaxAuthorLength > 0 && len(ship.Data.Author) > rules.MaxAuthorLength {
+		diags = append(diags, Diagnostic{ShipIndex: i, Message: fmt.Sprintf("author %q exceeds the %d character engine limit", ship.Data.Author, rules.MaxAuthorLength)})
+	}
+
+	bindingIdCounts := make(map[int]int, len(ship.Blocks))
+	counts := make(map[rsmships.BlockID]int)
+
+	for _, block := range ship.Blocks {
+		bindingIdCounts[block.BindingId]++
+		if block.Id != nil {
+			counts[*block.Id]++
+		}
+	}
+
+	for blockID, count := range counts {
+		rule, ok := rules.BlockRules[blockID]
+		if !ok {
+			continue
+		}
+
+		if rule.Banned {
+			diags = append(diags, Diagnostic{ShipIndex: i, Message: fmt.Sprintf("block %d is banned by this ruleset", blockID)})
+		} else if rule.MaxCount > 0 && count > rule.MaxCount {
+			diags = append(diags, Diagnostic{ShipIndex: i, Message: fmt.Sprintf("block %d appears %d times, exceeding the cap of %d", blockID, count, rule.MaxCount)})
+		}
+	}
+
+	for blockIdx, block := range ship.Blocks {
+		if block.Id != nil {
+			*fleetValue += rules.BlockValues[*block.Id]
+		}
+
+		if block.BindingId != 0 && bindingIdCounts[block.BindingId] < 2 {
+			diags = append(diags, Diagnostic{ShipIndex: i, Message: fmt.Sprintf("block %d has bindingId %d, which no other block in this ship declares", blockIdx, block.BindingId)})
+		}
+
+		if block.Command == nil {
+			continue
+		}
+
+		for _, flag := range block.Command.Flags {
+			if rules.AllowedFlags != nil && !containsFlag(rules.AllowedFlags, flag) {
+				diags = append(diags, Diagnostic{ShipIndex: i, Message: fmt.Sprintf("block %d sets command flag %q, which is not allowed by this ruleset", blockIdx, flag)})
+			}
+		}
+	}
+
+	if rules.RequireBindingDAG {
+		if cycle := findBindingCycle(ship.Blocks); cycle != nil {
+			diags = append(diags, Diagnostic{ShipIndex: i, Message: fmt.Sprintf("AI_BINDING chain forms a cycle through blocks %v", cycle)})
+		}
+	}
+
+	return diags
+}
+
+// findBindingCycle looks for a cycle among a ship's AI_BINDING chains. A
+// block with the AI_BINDING flag is modeled as an edge from its own index
+// to the first other block sharing its BindingId, since that is how
+// Reassembly links a bound block to the rest of its chain (?).
+func findBindingCycle(blocks []rsmships.Block) []int {
+	byBindingId := make(map[int][]int, len(blocks))
+	for idx, block := range blocks {
+		byBindingId[block.BindingId] = append(byBindingId[block.BindingId], idx)
+	}
+
+	edges := make(map[int]int)
+	for idx, block := range blocks {
+		if block.Command == nil || !containsFlag(block.Command.Flags, rsmships.FlagAIBinding) {
+			continue
+		}
+
+		for _, peer := range byBindingId[block.BindingId] {
+			if peer != idx {
+				edges[idx] = peer
+				break
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[int]int, len(edges))
+
+	var path []int
+	var visit func(idx int) []int
+	visit = func(idx int) []int {
+		switch state[idx] {
+		case visiting:
+			return append(append([]int{}, path...), idx)
+		case done:
+			return nil
+		}
+
+		state[idx] = visiting
+		path = append(path, idx)
+
+		if next, ok := edges[idx]; ok {
+			if cycle := visit(next); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[idx] = done
+		return nil
+	}
+
+	for idx := range edges {
+		if cycle := visit(idx); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+func containsInt(haystack []int, needle int) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFlag(haystack []rsmships.CommandFlag, needle rsmships.CommandFlag) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package tournament
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SomeDebris/rsmships-go"
+)
+
+func hasDiagnosticContaining(diags []Diagnostic, substr string) bool {
+	for _, d := range diags {
+		if strings.Contains(d.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func idPtr(id rsmships.BlockID) *rsmships.BlockID {
+	return &id
+}
+
+func TestLintFlagsBannedBlock(t *testing.T) {
+	ship := &rsmships.Ship{Blocks: []rsmships.Block{{Id: idPtr(1)}}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{BlockRules: map[rsmships.BlockID]BlockRule{1: {Banned: true}}}
+
+	diags := Lint(fleet, rules)
+	if !hasDiagnosticContaining(diags, "is banned by this ruleset") {
+		t.Errorf("Lint: expected a banned-block diagnostic, got %v", diags)
+	}
+}
+
+func TestLintAllowsUnbannedBlock(t *testing.T) {
+	ship := &rsmships.Ship{Blocks: []rsmships.Block{{Id: idPtr(1)}}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{BlockRules: map[rsmships.BlockID]BlockRule{2: {Banned: true}}}
+
+	diags := Lint(fleet, rules)
+	if hasDiagnosticContaining(diags, "is banned by this ruleset") {
+		t.Errorf("Lint: did not expect a banned-block diagnostic, got %v", diags)
+	}
+}
+
+func TestLintFlagsBlockCountOverCap(t *testing.T) {
+	ship := &rsmships.Ship{Blocks: []rsmships.Block{{Id: idPtr(1)}, {Id: idPtr(1)}, {Id: idPtr(1)}}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{BlockRules: map[rsmships.BlockID]BlockRule{1: {MaxCount: 2}}}
+
+	diags := Lint(fleet, rules)
+	if !hasDiagnosticContaining(diags, "exceeding the cap of 2") {
+		t.Errorf("Lint: expected a max-count diagnostic, got %v", diags)
+	}
+}
+
+func TestLintAllowsBlockCountUnderCap(t *testing.T) {
+	ship := &rsmships.Ship{Blocks: []rsmships.Block{{Id: idPtr(1)}, {Id: idPtr(1)}}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{BlockRules: map[rsmships.BlockID]BlockRule{1: {MaxCount: 2}}}
+
+	diags := Lint(fleet, rules)
+	if hasDiagnosticContaining(diags, "exceeding the cap of") {
+		t.Errorf("Lint: did not expect a max-count diagnostic, got %v", diags)
+	}
+}
+
+func TestLintFlagsFleetValueOverBudget(t *testing.T) {
+	ship := &rsmships.Ship{Blocks: []rsmships.Block{{Id: idPtr(1)}}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{BlockValues: map[rsmships.BlockID]float64{1: 10}, MaxFleetValue: 5}
+
+	diags := Lint(fleet, rules)
+	if !hasDiagnosticContaining(diags, "exceeds the tournament budget") {
+		t.Errorf("Lint: expected a fleet-value diagnostic, got %v", diags)
+	}
+}
+
+func TestLintAllowsFleetValueUnderBudget(t *testing.T) {
+	ship := &rsmships.Ship{Blocks: []rsmships.Block{{Id: idPtr(1)}}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{BlockValues: map[rsmships.BlockID]float64{1: 3}, MaxFleetValue: 5}
+
+	diags := Lint(fleet, rules)
+	if hasDiagnosticContaining(diags, "exceeds the tournament budget") {
+		t.Errorf("Lint: did not expect a fleet-value diagnostic, got %v", diags)
+	}
+}
+
+func TestLintFlagsDisallowedCommandFlag(t *testing.T) {
+	ship := &rsmships.Ship{
+		Blocks: []rsmships.Block{{Command: &rsmships.CommandData{Flags: rsmships.CommandFlags{rsmships.FlagAlwaysRush}}}},
+	}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{AllowedFlags: []rsmships.CommandFlag{rsmships.FlagAlwaysKite}}
+
+	diags := Lint(fleet, rules)
+	if !hasDiagnosticContaining(diags, "is not allowed by this ruleset") {
+		t.Errorf("Lint: expected a disallowed-flag diagnostic, got %v", diags)
+	}
+}
+
+func TestLintAllowsPermittedCommandFlag(t *testing.T) {
+	ship := &rsmships.Ship{
+		Blocks: []rsmships.Block{{Command: &rsmships.CommandData{Flags: rsmships.CommandFlags{rsmships.FlagAlwaysKite}}}},
+	}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{AllowedFlags: []rsmships.CommandFlag{rsmships.FlagAlwaysKite}}
+
+	diags := Lint(fleet, rules)
+	if hasDiagnosticContaining(diags, "is not allowed by this ruleset") {
+		t.Errorf("Lint: did not expect a disallowed-flag diagnostic, got %v", diags)
+	}
+}
+
+func TestLintFlagsAIBindingCycle(t *testing.T) {
+	bound := rsmships.CommandFlags{rsmships.FlagAIBinding}
+	ship := &rsmships.Ship{
+		Blocks: []rsmships.Block{
+			{BindingId: 5, Command: &rsmships.CommandData{Flags: bound}},
+			{BindingId: 5, Command: &rsmships.CommandData{Flags: bound}},
+		},
+	}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{RequireBindingDAG: true}
+
+	diags := Lint(fleet, rules)
+	if !hasDiagnosticContaining(diags, "forms a cycle") {
+		t.Errorf("Lint: expected an AI_BINDING cycle diagnostic, got %v", diags)
+	}
+}
+
+func TestLintAllowsAcyclicAIBindingChain(t *testing.T) {
+	ship := &rsmships.Ship{
+		Blocks: []rsmships.Block{
+			{BindingId: 1},
+			{BindingId: 1, Command: &rsmships.CommandData{Flags: rsmships.CommandFlags{rsmships.FlagAIBinding}}},
+		},
+	}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{RequireBindingDAG: true}
+
+	diags := Lint(fleet, rules)
+	if hasDiagnosticContaining(diags, "forms a cycle") {
+		t.Errorf("Lint: did not expect an AI_BINDING cycle diagnostic, got %v", diags)
+	}
+}
+
+func TestLintFlagsDuplicateShips(t *testing.T) {
+	ship1 := &rsmships.Ship{Data: rsmships.ShipData{Name: "Cutter", Author: "alice"}}
+	ship2 := &rsmships.Ship{Data: rsmships.ShipData{Name: "Cutter", Author: "alice"}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship1, ship2}}
+
+	diags := Lint(fleet, Ruleset{})
+	if !hasDiagnosticContaining(diags, "duplicate of ship 0") {
+		t.Errorf("Lint: expected a duplicate-ship diagnostic, got %v", diags)
+	}
+}
+
+func TestLintAllowsDistinctShips(t *testing.T) {
+	ship1 := &rsmships.Ship{Data: rsmships.ShipData{Name: "Cutter", Author: "alice"}}
+	ship2 := &rsmships.Ship{Data: rsmships.ShipData{Name: "Frigate", Author: "alice"}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship1, ship2}}
+
+	diags := Lint(fleet, Ruleset{})
+	if hasDiagnosticContaining(diags, "duplicate of ship") {
+		t.Errorf("Lint: did not expect a duplicate-ship diagnostic, got %v", diags)
+	}
+}
+
+func TestLintFlagsNameAndAuthorOverLength(t *testing.T) {
+	ship := &rsmships.Ship{Data: rsmships.ShipData{Name: "too long", Author: "also too long"}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{MaxNameLength: 4, MaxAuthorLength: 4}
+
+	diags := Lint(fleet, rules)
+	if !hasDiagnosticContaining(diags, "exceeds the 4 character engine limit") {
+		t.Errorf("Lint: expected name/author length diagnostics, got %v", diags)
+	}
+}
+
+func TestLintAllowsNameAndAuthorUnderLength(t *testing.T) {
+	ship := &rsmships.Ship{Data: rsmships.ShipData{Name: "ok", Author: "ok"}}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+	rules := Ruleset{MaxNameLength: 4, MaxAuthorLength: 4}
+
+	diags := Lint(fleet, rules)
+	if hasDiagnosticContaining(diags, "exceeds the") {
+		t.Errorf("Lint: did not expect a length diagnostic, got %v", diags)
+	}
+}
+
+func TestLintFlagsDisallowedFaction(t *testing.T) {
+	fleet := rsmships.Fleet{Faction: 50}
+	rules := Ruleset{Factions: []int{100, 101}}
+
+	diags := Lint(fleet, rules)
+	if !hasDiagnosticContaining(diags, "is not one of the tournament's allowed factions") {
+		t.Errorf("Lint: expected a faction diagnostic, got %v", diags)
+	}
+}
+
+func TestLintAllowsPermittedFaction(t *testing.T) {
+	fleet := rsmships.Fleet{Faction: 100}
+	rules := Ruleset{Factions: []int{100, 101}}
+
+	diags := Lint(fleet, rules)
+	if hasDiagnosticContaining(diags, "is not one of the tournament's allowed factions") {
+		t.Errorf("Lint: did not expect a faction diagnostic, got %v", diags)
+	}
+}
+
+// A block with a nonzero BindingId that no other block in the ship
+// shares is unresolved: nothing for it to bind to.
+func TestLintFlagsUnresolvedBindingId(t *testing.T) {
+	ship := &rsmships.Ship{
+		Blocks: []rsmships.Block{
+			{BindingId: 7},
+		},
+	}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+
+	diags := Lint(fleet, Ruleset{})
+	if !hasDiagnosticContaining(diags, "no other block in this ship declares") {
+		t.Errorf("Lint: expected an unresolved bindingId diagnostic, got %v", diags)
+	}
+}
+
+// Two blocks sharing a BindingId resolve each other and should not be
+// flagged.
+func TestLintAllowsResolvedBindingId(t *testing.T) {
+	ship := &rsmships.Ship{
+		Blocks: []rsmships.Block{
+			{BindingId: 7},
+			{BindingId: 7},
+		},
+	}
+	fleet := rsmships.Fleet{Blueprints: []*rsmships.Ship{ship}}
+
+	diags := Lint(fleet, Ruleset{})
+	if hasDiagnosticContaining(diags, "no other block in this ship declares") {
+		t.Errorf("Lint: did not expect an unresolved bindingId diagnostic, got %v", diags)
+	}
+}